@@ -9,53 +9,139 @@ import (
 	"bufio"
 	"errors"
 	"io"
+	"unicode/utf8"
 )
 
 var (
 	bufferTooSmall = errors.New("bitsinbytes: buffer too small")
+
+	errNegativeSeek     = errors.New("bitstream: negative position")
+	errInvalidWhence    = errors.New("bitstream: invalid whence")
+	errUnknownStreamLen = errors.New("bitstream: unknown stream length")
+	errNotByteAligned   = errors.New("bitstream: reader is not byte-aligned")
+	errCodeTooLarge     = errors.New("bitstream: value too large to encode or decode as an Elias gamma code")
 )
 
+// BitReader is the common interface implemented by Reader and the
+// composition helpers (NewSectionBitReader, NewMultiBitReader) that build on it.
+type BitReader interface {
+	// Bits returns the next bits up to a max of 64.
+	Bits(nBits int) (val uint64, err error)
+	// Bytes returns the number of requested bits inside a byte array.
+	Bytes(dst []byte, nBits int) (err error)
+	// Align discards the rest of the current byte's bits and byte-aligns
+	// the reader.
+	Align()
+	// BitPos reports the absolute read position in bits from the start
+	// of the stream.
+	BitPos() int64
+	// BitLen reports the total length of the stream in bits, or -1 if
+	// it cannot be determined.
+	BitLen() int64
+}
+
 // Reader reads many different types of values outside byte alignments.
 type Reader struct {
+	src    io.Reader
 	reader *bufio.Reader
 
+	shiftUp bool
+
 	offset uint
 	bits   byte
-
-	// Bits returns the next bits up to a max of 64.
-	Bits func(nBits int) (val uint64, err error)
-	// Bytes returns the number of requested bits inside a byte array.
-	Bytes func(dst []byte, nBits int) (err error)
+	// bitsValid is true when bits was actually populated from the stream
+	// by readByte and hasn't since been invalidated by Reset, Seek, or a
+	// byte-aligned Read that bypasses the cache, so
+	// that UnreadBits can tell a fully-consumed cached byte (safe to
+	// rewind into) apart from a reader that has no cached byte at all.
+	bitsValid bool
+
+	byteCount int64
+	bitLen    int64
+
+	// baseOffset is the underlying seeker's byte position at construction
+	// time, so that Seek's absolute offsets stay relative to the Reader's
+	// own start rather than the seeker's.
+	baseOffset int64
 }
 
 // New constructs a reader that shifts the next byte up to become
 // the most significant bits. Given data: 1010 0000 | 0000 0101,
 // a read of Bits(16) will yield: 0000 0101 1010 0000
 func New(reader io.Reader) *Reader {
-	r := &Reader{
-		offset: 8,
-		reader: bufio.NewReader(reader),
+	return &Reader{
+		offset:     8,
+		src:        reader,
+		reader:     bufio.NewReader(reader),
+		bitLen:     detectBitLen(reader),
+		baseOffset: detectBaseOffset(reader),
 	}
-
-	r.Bits = r.bitsLow
-	r.Bytes = r.bytesLow
-
-	return r
 }
 
 // NewShiftUp constructs a reader that shifts the current byte up to become
 // the most significant bits. Given data: 1010 0000 | 0000 0101,
 // a read of Bits(16) will yield: 1010 0000 0000 0101
 func NewShiftUp(reader io.Reader) *Reader {
-	r := &Reader{
-		offset: 8,
-		reader: bufio.NewReader(reader),
+	r := New(reader)
+	r.shiftUp = true
+	return r
+}
+
+// detectBitLen returns the length of reader in bits if it can be determined
+// without consuming it, or -1 if it cannot.
+func detectBitLen(reader io.Reader) int64 {
+	switch v := reader.(type) {
+	case interface{ Len() int }:
+		return int64(v.Len()) * 8
+	case io.Seeker:
+		cur, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		end, err := v.Seek(0, io.SeekEnd)
+		if err != nil {
+			return -1
+		}
+		if _, err = v.Seek(cur, io.SeekStart); err != nil {
+			return -1
+		}
+		return (end - cur) * 8
 	}
 
-	r.Bits = r.bitsHigh
-	r.Bytes = r.bytesHigh
+	return -1
+}
 
-	return r
+// detectBaseOffset returns the underlying seeker's current byte position,
+// so that later absolute Seeks on the Reader can be computed relative to
+// it rather than to the seeker's own byte 0. It returns 0 if reader is not
+// an io.Seeker or its position cannot be determined.
+func detectBaseOffset(reader io.Reader) int64 {
+	seeker, ok := reader.(io.Seeker)
+	if !ok {
+		return 0
+	}
+
+	cur, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0
+	}
+	return cur
+}
+
+// Bits returns the next bits up to a max of 64.
+func (r *Reader) Bits(nBits int) (val uint64, err error) {
+	if r.shiftUp {
+		return r.bitsHigh(nBits)
+	}
+	return r.bitsLow(nBits)
+}
+
+// Bytes returns the number of requested bits inside a byte array.
+func (r *Reader) Bytes(dst []byte, nBits int) (err error) {
+	if r.shiftUp {
+		return r.bytesHigh(dst, nBits)
+	}
+	return r.bytesLow(dst, nBits)
 }
 
 // Align discards the rest of the current byte's bits and byte-aligns the reader.
@@ -63,6 +149,111 @@ func (r *Reader) Align() {
 	r.offset = 8
 }
 
+// BitPos returns the absolute read position in bits from the start of the
+// stream.
+func (r *Reader) BitPos() int64 {
+	return r.byteCount*8 - int64(8-r.offset)
+}
+
+// BitLen returns the total length of the stream in bits, or -1 if it
+// could not be determined from the underlying reader.
+func (r *Reader) BitLen() int64 {
+	return r.bitLen
+}
+
+// Seek sets the bit offset for the next read, interpreted according to
+// whence: io.SeekStart, io.SeekCurrent, or io.SeekEnd (which requires
+// BitLen to be known). It returns the new absolute bit offset.
+//
+// If the underlying reader is an io.Seeker, Seek repositions it directly.
+// Otherwise only forward seeks are possible, and are implemented by
+// discarding bits until the target offset is reached.
+func (r *Reader) Seek(bitOffset int64, whence int) (int64, error) {
+	var target int64
+	switch whence {
+	case io.SeekStart:
+		target = bitOffset
+	case io.SeekCurrent:
+		target = r.BitPos() + bitOffset
+	case io.SeekEnd:
+		if r.bitLen < 0 {
+			return r.BitPos(), errUnknownStreamLen
+		}
+		target = r.bitLen + bitOffset
+	default:
+		return r.BitPos(), errInvalidWhence
+	}
+
+	if target < 0 {
+		return r.BitPos(), errNegativeSeek
+	}
+
+	if seeker, ok := r.src.(io.Seeker); ok {
+		byteOffset := target / 8
+		if _, err := seeker.Seek(r.baseOffset+byteOffset, io.SeekStart); err != nil {
+			return r.BitPos(), err
+		}
+
+		r.reader.Reset(r.src)
+		r.byteCount = byteOffset
+		r.offset = 8
+		r.bits = 0
+		r.bitsValid = false
+
+		if bitRem := int(target % 8); bitRem > 0 {
+			if _, err := r.Bits(bitRem); err != nil {
+				return r.BitPos(), err
+			}
+		}
+
+		return target, nil
+	}
+
+	cur := r.BitPos()
+	if target < cur {
+		return cur, errSeekBackwards
+	}
+
+	for toSkip := target - cur; toSkip > 0; {
+		n := toSkip
+		if n > 64 {
+			n = 64
+		}
+		if _, err := r.Bits(int(n)); err != nil {
+			return r.BitPos(), err
+		}
+		toSkip -= n
+	}
+
+	return target, nil
+}
+
+// Reset discards any buffered data and makes the Reader read from reader
+// from the start, keeping its current mode (New vs NewShiftUp).
+func (r *Reader) Reset(reader io.Reader) {
+	r.src = reader
+	r.reader.Reset(reader)
+	r.offset = 8
+	r.bits = 0
+	r.bitsValid = false
+	r.byteCount = 0
+	r.bitLen = detectBitLen(reader)
+	r.baseOffset = detectBaseOffset(reader)
+}
+
+// readByte reads a single byte from the underlying reader, tracking how
+// many bytes have been consumed for BitPos.
+func (r *Reader) readByte() (byte, error) {
+	b, err := r.reader.ReadByte()
+	if err == nil {
+		r.byteCount++
+		r.bitsValid = true
+	} else {
+		r.bitsValid = false
+	}
+	return b, err
+}
+
 func (r *Reader) bitsLow(nBits int) (val uint64, err error) {
 	if nBits > 64 {
 		panic("Can only read 64 bits at a time.")
@@ -72,8 +263,9 @@ func (r *Reader) bitsLow(nBits int) (val uint64, err error) {
 	for nBits > 0 {
 		if r.offset == 8 {
 			r.offset = 0
-			r.bits, err = r.reader.ReadByte()
+			r.bits, err = r.readByte()
 			if err != nil {
+				r.offset = 8
 				return val, err
 			}
 		}
@@ -97,26 +289,70 @@ func (r *Reader) bitsLow(nBits int) (val uint64, err error) {
 // Byte from the reader.
 func (r *Reader) Byte() (byte, error) {
 	if r.offset == 8 {
-		return r.reader.ReadByte()
+		return r.readByte()
 	}
 
 	bits, err := r.Bits(8)
 	return byte(bits), err
 }
 
+// ReadByte reads a single byte, formally implementing io.ByteReader so a
+// byte-aligned Reader can be passed to standard library decoders such as
+// binary.ReadUvarint. It returns errNotByteAligned if the reader currently
+// sits mid-byte; call Align first.
+func (r *Reader) ReadByte() (byte, error) {
+	if r.offset != 8 {
+		return 0, errNotByteAligned
+	}
+	return r.readByte()
+}
+
+// ReadRune reads a single UTF-8 encoded rune, implementing io.RuneReader.
+// Like ReadByte, it requires the reader to be byte-aligned and returns
+// errNotByteAligned otherwise.
+func (r *Reader) ReadRune() (ru rune, size int, err error) {
+	if r.offset != 8 {
+		return 0, 0, errNotByteAligned
+	}
+
+	var buf [utf8.UTFMax]byte
+	n := 0
+	for {
+		var b byte
+		if b, err = r.readByte(); err != nil {
+			return 0, 0, err
+		}
+		buf[n] = b
+		n++
+
+		if n == 1 && b < utf8.RuneSelf {
+			return rune(b), 1, nil
+		}
+		if utf8.FullRune(buf[:n]) || n == utf8.UTFMax {
+			ru, size = utf8.DecodeRune(buf[:n])
+			return ru, size, nil
+		}
+	}
+}
+
 // Read whole bytes from the reader.
 func (r *Reader) Read(dst []byte) (int, error) {
 	if r.offset == 8 {
-		ret, err := r.reader.Read(dst)
-
-		// bufio doesn't fill it's buffer until it's completely empty.
-		// if a short read happens with no error: retry.
-		if err == nil && len(dst) != ret {
-			again, e := r.reader.Read(dst[ret:])
-			return again + ret, e
+		n := 0
+		var err error
+		// bufio doesn't fill its buffer until it's completely empty, and
+		// the underlying reader may itself return arbitrarily short reads,
+		// so keep retrying until dst is full or we hit an error.
+		for n < len(dst) && err == nil {
+			var nn int
+			nn, err = r.reader.Read(dst[n:])
+			n += nn
 		}
-
-		return ret, err
+		r.byteCount += int64(n)
+		if n > 0 {
+			r.bitsValid = false
+		}
+		return n, err
 	}
 
 	n := 0
@@ -145,8 +381,9 @@ func (r *Reader) bytesLow(dst []byte, nBits int) (err error) {
 		if r.offset == 8 {
 			r.offset = 0
 
-			r.bits, err = r.reader.ReadByte()
+			r.bits, err = r.readByte()
 			if err != nil {
+				r.offset = 8
 				return err
 			}
 		}
@@ -184,8 +421,9 @@ func (r *Reader) bitsHigh(nBits int) (val uint64, err error) {
 	for nBits > 0 {
 		if r.offset == 8 {
 			r.offset = 0
-			r.bits, err = r.reader.ReadByte()
+			r.bits, err = r.readByte()
 			if err != nil {
+				r.offset = 8
 				return val, err
 			}
 		}
@@ -216,8 +454,9 @@ func (r *Reader) bytesHigh(dst []byte, nBits int) (err error) {
 		if r.offset == 8 {
 			r.offset = 0
 
-			r.bits, err = r.reader.ReadByte()
+			r.bits, err = r.readByte()
 			if err != nil {
+				r.offset = 8
 				return err
 			}
 		}
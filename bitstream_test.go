@@ -2,11 +2,17 @@ package bitstream
 
 import (
 	"bytes"
+	"encoding/binary"
 	"io"
 	"testing"
+	"testing/iotest"
 )
 
-var reader io.Reader = &Reader{}
+var (
+	reader     io.Reader     = &Reader{}
+	byteReader io.ByteReader = &Reader{}
+	runeReader io.RuneReader = &Reader{}
+)
 
 func toBinInt(s string) uint64 {
 	var val uint64
@@ -257,8 +263,10 @@ func TestReader_FastPathError(t *testing.T) {
 		t.Error("Didn't read 4095:", n)
 	}
 
-	if n, err := b.Read(buf); err != nil {
-		t.Error(err)
+	// The remaining 2 bytes and the EOF both surface on this call now that
+	// Read retries internally until dst is full or an error occurs.
+	if n, err := b.Read(buf); err != io.EOF {
+		t.Error("Reader should be finished:", err)
 	} else if n != 2 {
 		t.Error("Didn't read 2:", n)
 	}
@@ -318,3 +326,138 @@ func TestReader_BytesHigh(t *testing.T) {
 		t.Errorf("Wrong values: % 02X", buf[0])
 	}
 }
+
+func TestReader_ReadByte(t *testing.T) {
+	data := []byte{0xF0, 0xFF}
+
+	b := New(bytes.NewBuffer(data))
+
+	if val, err := b.ReadByte(); err != nil {
+		t.Error(err)
+	} else if val != 0xF0 {
+		t.Errorf("Wrong value: % 02X", val)
+	}
+}
+
+func TestReader_ReadByteNotAligned(t *testing.T) {
+	data := []byte{0xF0, 0xFF}
+
+	b := New(bytes.NewBuffer(data))
+
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, err := b.ReadByte(); err != errNotByteAligned {
+		t.Error("Expected errNotByteAligned:", err)
+	}
+}
+
+func TestReader_ReadByteWithBinaryUvarint(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	want := uint64(300)
+	var enc [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(enc[:], want)
+	buf.Write(enc[:n])
+
+	b := New(buf)
+
+	got, err := binary.ReadUvarint(b)
+	if err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if got != want {
+		t.Errorf("got %d want %d", got, want)
+	}
+}
+
+func TestReader_ReadRune(t *testing.T) {
+	data := "aé中"
+
+	b := New(bytes.NewBufferString(data))
+
+	for _, want := range data {
+		got, size, err := b.ReadRune()
+		if err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+		if size == 0 {
+			t.Error("size should not be 0")
+		}
+	}
+}
+
+func TestReader_ReadRuneNotAligned(t *testing.T) {
+	b := New(bytes.NewBufferString("a"))
+
+	if _, err := b.Bits(1); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, _, err := b.ReadRune(); err != errNotByteAligned {
+		t.Error("Expected errNotByteAligned:", err)
+	}
+}
+
+func TestReader_ShortReads(t *testing.T) {
+	data := []byte{0xDE, 0xAD, 0xBE, 0xEF, 0x12, 0x34}
+
+	wrap := map[string]func(io.Reader) io.Reader{
+		"OneByteReader": iotest.OneByteReader,
+		"HalfReader":    iotest.HalfReader,
+		"PartialReader": func(r io.Reader) io.Reader { return &partialReader{r: r, n: 1} },
+	}
+
+	for name, wrapReader := range wrap {
+		t.Run(name+"/Bits", func(t *testing.T) {
+			b := New(wrapReader(bytes.NewReader(data)))
+			if val, err := b.Bits(32); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			} else if val != 0xEFBEADDE {
+				t.Errorf("Wrong Value: % 08X", val)
+			}
+		})
+
+		t.Run(name+"/Bytes", func(t *testing.T) {
+			b := New(wrapReader(bytes.NewReader(data)))
+			dst := make([]byte, 4)
+			if err := b.Bytes(dst, 32); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			} else if !bytes.Equal(dst, data[:4]) {
+				t.Errorf("Wrong Value: % 02X", dst)
+			}
+		})
+
+		t.Run(name+"/Byte", func(t *testing.T) {
+			b := New(wrapReader(bytes.NewReader(data)))
+			for _, want := range data {
+				if got, err := b.Byte(); err != nil {
+					t.Fatal("Unexpected Error:", err)
+				} else if got != want {
+					t.Errorf("got % 02X want % 02X", got, want)
+				}
+			}
+		})
+
+		t.Run(name+"/Read", func(t *testing.T) {
+			b := New(wrapReader(bytes.NewReader(data)))
+			dst := make([]byte, len(data))
+			// A single call to Read must fully satisfy dst by retrying
+			// internally, regardless of how short the underlying reads are.
+			n, err := b.Read(dst)
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if n != len(data) {
+				t.Error("Wrong length:", n)
+			}
+			if !bytes.Equal(dst, data) {
+				t.Errorf("Wrong Value: % 02X", dst)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package bitstream
+
+import (
+	"io"
+	"math"
+	"math/bits"
+)
+
+// ReadUnary counts the leading zero bits up to and including a terminating
+// one bit, and returns the number of zero bits read. It works identically
+// in both low and shift-up mode.
+func (r *Reader) ReadUnary() (uint64, error) {
+	return r.readUnary(1)
+}
+
+// ReadUnaryOnes is the inverse polarity of ReadUnary: it counts leading one
+// bits up to and including a terminating zero bit.
+func (r *Reader) ReadUnaryOnes() (uint64, error) {
+	return r.readUnary(0)
+}
+
+func (r *Reader) readUnary(terminator uint64) (uint64, error) {
+	var n uint64
+	for {
+		bit, err := r.Bits(1)
+		if err != nil {
+			if err == io.EOF {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		if bit == terminator {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// ReadEliasGamma reads an Elias gamma code: N zero bits, a terminating one,
+// and then N more bits forming the low bits of an (N+1)-bit payload. It
+// returns payload-1. It returns errCodeTooLarge if N exceeds 63, since no
+// valid payload can then fit in a uint64.
+func (r *Reader) ReadEliasGamma() (uint64, error) {
+	n, err := r.ReadUnary()
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	if n > 63 {
+		return 0, errCodeTooLarge
+	}
+
+	rest, err := r.Bits(int(n))
+	if err != nil {
+		if err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+
+	return ((uint64(1) << n) | rest) - 1, nil
+}
+
+// ReadRice reads a Golomb-Rice code with parameter k: a unary quotient q
+// followed by k remainder bits r, and returns q<<k | r.
+func (r *Reader) ReadRice(k uint) (uint64, error) {
+	q, err := r.ReadUnary()
+	if err != nil {
+		return 0, err
+	}
+
+	var rem uint64
+	if k > 0 {
+		rem, err = r.Bits(int(k))
+		if err != nil {
+			if err == io.EOF {
+				return 0, io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+	}
+
+	return (q << k) | rem, nil
+}
+
+// ReadRiceSigned reads a Golomb-Rice code with parameter k and zig-zag
+// decodes the result into a signed value.
+func (r *Reader) ReadRiceSigned(k uint) (int64, error) {
+	u, err := r.ReadRice(k)
+	if err != nil {
+		return 0, err
+	}
+
+	return zigzagDecode(u), nil
+}
+
+func zigzagDecode(u uint64) int64 {
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+// WriteUnary writes n zero bits followed by a terminating one bit.
+func (w *Writer) WriteUnary(n uint64) error {
+	for ; n > 0; n-- {
+		if err := w.WriteBits(0, 1); err != nil {
+			return err
+		}
+	}
+	return w.WriteBits(1, 1)
+}
+
+// WriteUnaryOnes is the inverse polarity of WriteUnary: it writes n one
+// bits followed by a terminating zero bit.
+func (w *Writer) WriteUnaryOnes(n uint64) error {
+	for ; n > 0; n-- {
+		if err := w.WriteBits(1, 1); err != nil {
+			return err
+		}
+	}
+	return w.WriteBits(0, 1)
+}
+
+// WriteEliasGamma writes val+1 as an Elias gamma code, the inverse of
+// ReadEliasGamma. val must not be math.MaxUint64, since val+1 would then
+// overflow; it returns errCodeTooLarge in that case.
+func (w *Writer) WriteEliasGamma(val uint64) error {
+	if val == math.MaxUint64 {
+		return errCodeTooLarge
+	}
+
+	payload := val + 1
+	n := bits.Len64(payload) - 1
+
+	if err := w.WriteUnary(uint64(n)); err != nil {
+		return err
+	}
+	if n == 0 {
+		return nil
+	}
+
+	rest := payload & ((uint64(1) << uint(n)) - 1)
+	return w.WriteBits(rest, n)
+}
+
+// WriteRice writes val as a Golomb-Rice code with parameter k, the inverse
+// of ReadRice.
+func (w *Writer) WriteRice(val uint64, k uint) error {
+	if err := w.WriteUnary(val >> k); err != nil {
+		return err
+	}
+	if k == 0 {
+		return nil
+	}
+
+	rem := val & ((uint64(1) << k) - 1)
+	return w.WriteBits(rem, int(k))
+}
+
+// WriteRiceSigned zig-zag encodes val and writes it as a Golomb-Rice code
+// with parameter k.
+func (w *Writer) WriteRiceSigned(val int64, k uint) error {
+	return w.WriteRice(zigzagEncode(val), k)
+}
+
+func zigzagEncode(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
@@ -0,0 +1,359 @@
+package bitstream
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+)
+
+func TestReader_ReadUnary(t *testing.T) {
+	tests := []uint64{0, 1, 5, 9, 63}
+
+	for _, shift := range []bool{false, true} {
+		for _, want := range tests {
+			buf := new(bytes.Buffer)
+			var w *Writer
+			if shift {
+				w = NewWriterShiftUp(buf)
+			} else {
+				w = NewWriter(buf)
+			}
+			if err := w.WriteUnary(want); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			var r *Reader
+			if shift {
+				r = NewShiftUp(bytes.NewReader(buf.Bytes()))
+			} else {
+				r = New(bytes.NewReader(buf.Bytes()))
+			}
+
+			got, err := r.ReadUnary()
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if got != want {
+				t.Errorf("shift=%v: got %d want %d", shift, got, want)
+			}
+		}
+	}
+}
+
+func TestReader_ReadUnaryOnes(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.WriteUnaryOnes(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	r := New(bytes.NewReader(buf.Bytes()))
+	got, err := r.ReadUnaryOnes()
+	if err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if got != 4 {
+		t.Errorf("got %d want 4", got)
+	}
+}
+
+func TestReader_ReadUnaryEOF(t *testing.T) {
+	r := New(bytes.NewReader([]byte{0x00}))
+	if _, err := r.ReadUnary(); err != io.ErrUnexpectedEOF {
+		t.Error("Expected io.ErrUnexpectedEOF:", err)
+	}
+}
+
+func TestReader_ReadEliasGamma(t *testing.T) {
+	tests := []uint64{0, 1, 2, 3, 6, 42, 1000}
+
+	for _, shift := range []bool{false, true} {
+		for _, want := range tests {
+			buf := new(bytes.Buffer)
+			var w *Writer
+			if shift {
+				w = NewWriterShiftUp(buf)
+			} else {
+				w = NewWriter(buf)
+			}
+			if err := w.WriteEliasGamma(want); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			var r *Reader
+			if shift {
+				r = NewShiftUp(bytes.NewReader(buf.Bytes()))
+			} else {
+				r = New(bytes.NewReader(buf.Bytes()))
+			}
+
+			got, err := r.ReadEliasGamma()
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if got != want {
+				t.Errorf("shift=%v: got %d want %d", shift, got, want)
+			}
+		}
+	}
+}
+
+func TestReader_ReadEliasGammaEOF(t *testing.T) {
+	r := New(bytes.NewReader([]byte{0x00}))
+	if _, err := r.ReadEliasGamma(); err != io.ErrUnexpectedEOF {
+		t.Error("Expected io.ErrUnexpectedEOF:", err)
+	}
+}
+
+func TestReader_ReadEliasGammaTooLarge(t *testing.T) {
+	// 64 zero bits followed by a terminating one: a well-formed but
+	// oversized unary prefix whose 65-bit payload cannot fit in a uint64.
+	data := append(make([]byte, 8), 0x01)
+	r := New(bytes.NewReader(data))
+	if _, err := r.ReadEliasGamma(); err != errCodeTooLarge {
+		t.Error("Expected errCodeTooLarge:", err)
+	}
+}
+
+// TestReader_ReadEliasGammaKnownEncoding decodes literal, hand-built bit
+// patterns instead of round-tripping through WriteEliasGamma, so a bug
+// shared by both sides of the codec can't hide from it.
+func TestReader_ReadEliasGammaKnownEncoding(t *testing.T) {
+	tests := []struct {
+		want uint64
+		data byte
+	}{
+		{0, toBin("0000 0001")},
+		{3, toBin("0000 0100")},
+		{6, toBin("0001 1100")},
+	}
+
+	for _, test := range tests {
+		r := New(bytes.NewReader([]byte{test.data}))
+		got, err := r.ReadEliasGamma()
+		if err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if got != test.want {
+			t.Errorf("data=%08b: got %d want %d", test.data, got, test.want)
+		}
+	}
+}
+
+func TestReader_ReadRice(t *testing.T) {
+	tests := []uint64{0, 3, 13, 255, 4096}
+
+	for _, k := range []uint{0, 2, 4} {
+		for _, want := range tests {
+			buf := new(bytes.Buffer)
+			w := NewWriter(buf)
+			if err := w.WriteRice(want, k); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			r := New(bytes.NewReader(buf.Bytes()))
+			got, err := r.ReadRice(k)
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if got != want {
+				t.Errorf("k=%d: got %d want %d", k, got, want)
+			}
+		}
+	}
+}
+
+// TestReader_ReadRiceKnownEncoding decodes literal, hand-built bit patterns
+// instead of round-tripping through WriteRice.
+func TestReader_ReadRiceKnownEncoding(t *testing.T) {
+	const k = 2
+	tests := []struct {
+		want uint64
+		data byte
+	}{
+		{0, toBin("0000 0001")},
+		{7, toBin("0000 1110")},
+		{13, toBin("0001 1000")},
+	}
+
+	for _, test := range tests {
+		r := New(bytes.NewReader([]byte{test.data}))
+		got, err := r.ReadRice(k)
+		if err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if got != test.want {
+			t.Errorf("data=%08b: got %d want %d", test.data, got, test.want)
+		}
+	}
+}
+
+func TestReader_ReadRiceSigned(t *testing.T) {
+	tests := []int64{0, 5, -5, 1000, -1000}
+
+	for _, k := range []uint{0, 3, 5} {
+		for _, want := range tests {
+			buf := new(bytes.Buffer)
+			w := NewWriter(buf)
+			if err := w.WriteRiceSigned(want, k); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			r := New(bytes.NewReader(buf.Bytes()))
+			got, err := r.ReadRiceSigned(k)
+			if err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if got != want {
+				t.Errorf("k=%d: got %d want %d", k, got, want)
+			}
+		}
+	}
+}
+
+// TestReader_ReadRiceSignedKnownEncoding decodes literal, hand-built bit
+// patterns instead of round-tripping through WriteRiceSigned.
+func TestReader_ReadRiceSignedKnownEncoding(t *testing.T) {
+	const k = 1
+	tests := []struct {
+		want int64
+		data byte
+	}{
+		{0, toBin("0000 0001")},
+		{2, toBin("0000 0100")},
+		{-3, toBin("0000 1100")},
+	}
+
+	for _, test := range tests {
+		r := New(bytes.NewReader([]byte{test.data}))
+		got, err := r.ReadRiceSigned(k)
+		if err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if got != test.want {
+			t.Errorf("data=%08b: got %d want %d", test.data, got, test.want)
+		}
+	}
+}
+
+// TestWriter_WriteEliasGammaKnownEncoding checks WriteEliasGamma's raw
+// output against a hand-computed bit pattern, independently of the reader.
+func TestWriter_WriteEliasGammaKnownEncoding(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.WriteEliasGamma(6); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	want := toBin("0001 1100")
+	if got := buf.Bytes(); len(got) != 1 || got[0] != want {
+		t.Errorf("got % 02X want % 02X", got, want)
+	}
+}
+
+func TestWriter_WriteEliasGammaOverflow(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+	if err := w.WriteEliasGamma(math.MaxUint64); err != errCodeTooLarge {
+		t.Error("Expected errCodeTooLarge:", err)
+	}
+}
+
+func TestCodes_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		shift bool
+	}{
+		{"low", false},
+		{"high", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			var w *Writer
+			if test.shift {
+				w = NewWriterShiftUp(buf)
+			} else {
+				w = NewWriter(buf)
+			}
+
+			unaries := []uint64{0, 1, 5, 9}
+			gammas := []uint64{0, 1, 6, 42}
+			rices := []uint64{0, 3, 13, 255}
+
+			for _, v := range unaries {
+				if err := w.WriteUnary(v); err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+			}
+			for _, v := range gammas {
+				if err := w.WriteEliasGamma(v); err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+			}
+			for _, v := range rices {
+				if err := w.WriteRice(v, 4); err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			var r *Reader
+			if test.shift {
+				r = NewShiftUp(bytes.NewReader(buf.Bytes()))
+			} else {
+				r = New(bytes.NewReader(buf.Bytes()))
+			}
+
+			for _, want := range unaries {
+				got, err := r.ReadUnary()
+				if err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+				if got != want {
+					t.Errorf("unary: got %d want %d", got, want)
+				}
+			}
+			for _, want := range gammas {
+				got, err := r.ReadEliasGamma()
+				if err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+				if got != want {
+					t.Errorf("gamma: got %d want %d", got, want)
+				}
+			}
+			for _, want := range rices {
+				got, err := r.ReadRice(4)
+				if err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+				if got != want {
+					t.Errorf("rice: got %d want %d", got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package bitstream
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errSeekBackwards = errors.New("bitstream: cannot seek backwards on a non-seekable reader")
+)
+
+// bitSeeker is satisfied by BitReaders (such as *Reader) that can seek to an
+// absolute bit offset. It is checked for internally by NewSectionBitReader;
+// it is not part of the BitReader interface itself.
+type bitSeeker interface {
+	Seek(bitOffset int64, whence int) (int64, error)
+}
+
+// sectionBitReader exposes a bounded window of another BitReader.
+type sectionBitReader struct {
+	r         BitReader
+	bitOffset int64
+	nBits     int64
+
+	pos     int64
+	started bool
+}
+
+// NewSectionBitReader returns a BitReader that reads the nBits starting at
+// bitOffset within r, as if it were its own bitstream. Reads past nBits
+// return io.EOF. Before its first read the section moves r to bitOffset: if
+// r supports seeking it seeks there, even back from a later position;
+// otherwise it skips forward and fails if r is already past bitOffset. The
+// section cannot be rewound, so it can be read only once.
+func NewSectionBitReader(r BitReader, bitOffset, nBits int64) BitReader {
+	return &sectionBitReader{r: r, bitOffset: bitOffset, nBits: nBits}
+}
+
+func (s *sectionBitReader) position() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	if seeker, ok := s.r.(bitSeeker); ok {
+		_, err := seeker.Seek(s.bitOffset, io.SeekStart)
+		return err
+	}
+
+	cur := s.r.BitPos()
+	if cur > s.bitOffset {
+		return errSeekBackwards
+	}
+
+	for toSkip := s.bitOffset - cur; toSkip > 0; {
+		n := toSkip
+		if n > 64 {
+			n = 64
+		}
+		if _, err := s.r.Bits(int(n)); err != nil {
+			return err
+		}
+		toSkip -= n
+	}
+
+	return nil
+}
+
+// Bits returns the next bits up to a max of 64.
+func (s *sectionBitReader) Bits(nBits int) (uint64, error) {
+	if err := s.position(); err != nil {
+		return 0, err
+	}
+
+	remaining := s.nBits - s.pos
+	if remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	eof := false
+	if int64(nBits) > remaining {
+		nBits = int(remaining)
+		eof = true
+	}
+
+	val, err := s.r.Bits(nBits)
+	s.pos += int64(nBits)
+	if err != nil {
+		return val, err
+	}
+	if eof {
+		return val, io.EOF
+	}
+
+	return val, nil
+}
+
+// Bytes returns the number of requested bits inside a byte array.
+func (s *sectionBitReader) Bytes(dst []byte, nBits int) error {
+	if err := s.position(); err != nil {
+		return err
+	}
+
+	remaining := s.nBits - s.pos
+	if int64(nBits) > remaining {
+		return io.EOF
+	}
+
+	err := s.r.Bytes(dst, nBits)
+	s.pos += int64(nBits)
+	return err
+}
+
+// Align discards the rest of the current byte's bits and byte-aligns the
+// underlying reader.
+func (s *sectionBitReader) Align() {
+	before := s.r.BitPos()
+	s.r.Align()
+	if s.started {
+		s.pos += s.r.BitPos() - before
+	}
+}
+
+// BitPos returns the read position in bits from the start of the section.
+func (s *sectionBitReader) BitPos() int64 {
+	return s.pos
+}
+
+// BitLen returns the length of the section in bits.
+func (s *sectionBitReader) BitLen() int64 {
+	return s.nBits
+}
+
+// multiBitReader concatenates several BitReaders into a single bitstream,
+// re-packing bits across the transition between one reader and the next
+// rather than byte-aligning at the boundary.
+type multiBitReader struct {
+	readers []BitReader
+	idx     int
+	pos     int64
+}
+
+// NewMultiBitReader returns a BitReader that reads from rs in order,
+// concatenating their bits across non-byte boundaries.
+func NewMultiBitReader(rs ...BitReader) BitReader {
+	return &multiBitReader{readers: rs}
+}
+
+// Bits returns the next bits up to a max of 64.
+func (m *multiBitReader) Bits(nBits int) (val uint64, err error) {
+	if nBits > 64 {
+		panic("Can only read 64 bits at a time.")
+	}
+
+	var bitOffset uint
+	remaining := nBits
+
+	for remaining > 0 {
+		if m.idx >= len(m.readers) {
+			return val, io.EOF
+		}
+
+		cur := m.readers[m.idx]
+		before := cur.BitPos()
+		chunk, cerr := cur.Bits(remaining)
+		consumed := int(cur.BitPos() - before)
+
+		if consumed > 0 {
+			mask := uint64(1)<<uint(consumed) - 1
+			val |= (chunk & mask) << bitOffset
+			bitOffset += uint(consumed)
+			remaining -= consumed
+			m.pos += int64(consumed)
+		}
+
+		if cerr == io.EOF {
+			m.idx++
+			continue
+		} else if cerr != nil {
+			return val, cerr
+		}
+	}
+
+	return val, nil
+}
+
+// Bytes returns the number of requested bits inside a byte array.
+func (m *multiBitReader) Bytes(dst []byte, nBits int) error {
+	if len(dst) < (nBits+7)/8 {
+		return bufferTooSmall
+	}
+
+	var byteOffset int
+	var bitOffset uint
+
+	for nBits > 0 {
+		if bitOffset == 8 {
+			bitOffset = 0
+			byteOffset++
+		}
+
+		toRead := nBits
+		if toRead > 8-int(bitOffset) {
+			toRead = 8 - int(bitOffset)
+		}
+
+		chunk, err := m.Bits(toRead)
+		if err != nil {
+			return err
+		}
+
+		dst[byteOffset] |= byte(chunk) << bitOffset
+		bitOffset += uint(toRead)
+		nBits -= toRead
+	}
+
+	return nil
+}
+
+// Align byte-aligns the currently active underlying reader.
+func (m *multiBitReader) Align() {
+	if m.idx < len(m.readers) {
+		cur := m.readers[m.idx]
+		before := cur.BitPos()
+		cur.Align()
+		m.pos += cur.BitPos() - before
+	}
+}
+
+// BitPos returns the read position in bits from the start of the
+// concatenated stream.
+func (m *multiBitReader) BitPos() int64 {
+	return m.pos
+}
+
+// BitLen returns the total length of the concatenated stream in bits, or
+// -1 if any of the underlying readers' lengths could not be determined.
+func (m *multiBitReader) BitLen() int64 {
+	var total int64
+	for _, r := range m.readers {
+		l := r.BitLen()
+		if l < 0 {
+			return -1
+		}
+		total += l
+	}
+	return total
+}
@@ -0,0 +1,132 @@
+package bitstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestReader_BitPosBitLen(t *testing.T) {
+	data := []byte{0xFF, 0x0F}
+
+	b := New(bytes.NewReader(data))
+
+	if l := b.BitLen(); l != 16 {
+		t.Error("Wrong bit length:", l)
+	}
+	if p := b.BitPos(); p != 0 {
+		t.Error("Wrong bit position:", p)
+	}
+
+	if _, err := b.Bits(5); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if p := b.BitPos(); p != 5 {
+		t.Error("Wrong bit position:", p)
+	}
+
+	if _, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if p := b.BitPos(); p != 13 {
+		t.Error("Wrong bit position:", p)
+	}
+}
+
+func TestReader_BitLenUnknown(t *testing.T) {
+	b := New(io.LimitReader(bytes.NewReader([]byte{0x00}), 1))
+	if l := b.BitLen(); l != -1 {
+		t.Error("Expected unknown bit length:", l)
+	}
+}
+
+func TestSectionBitReader(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101"), toBin("1111 0000")}
+
+	b := New(bytes.NewBuffer(data))
+	s := NewSectionBitReader(b, 8, 12)
+
+	if l := s.BitLen(); l != 12 {
+		t.Error("Wrong bit length:", l)
+	}
+
+	if val, err := s.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+
+	if val, err := s.Bits(4); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("0000") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+
+	if _, err := s.Bits(1); err != io.EOF {
+		t.Error("Expected EOF:", err)
+	}
+}
+
+func TestSectionBitReader_SeeksBack(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+	// Move past the section's start; since the underlying bytes.Reader
+	// is an io.Seeker, the section reader can rewind to it.
+	if _, err := b.Bits(16); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	s := NewSectionBitReader(b, 0, 8)
+	if val, err := s.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("0000 1111") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestSectionBitReader_CannotSeekBack(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	// Hide the io.Seeker on the underlying reader so the section reader
+	// has no way to rewind.
+	b := New(struct{ io.Reader }{bytes.NewReader(data)})
+	if _, err := b.Bits(16); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	s := NewSectionBitReader(b, 0, 8)
+	if _, err := s.Bits(8); err != errSeekBackwards {
+		t.Error("Expected errSeekBackwards:", err)
+	}
+}
+
+func TestMultiBitReader(t *testing.T) {
+	a := New(bytes.NewBuffer([]byte{toBin("0000 0111")}))
+	c := New(bytes.NewBuffer([]byte{toBin("1010 0000")}))
+
+	m := NewMultiBitReader(a, c)
+
+	if val, err := m.Bits(3); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("111") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+
+	// Crosses the boundary between a and c, re-packing instead of
+	// re-aligning on the byte boundary: the remaining 5 bits of a (all
+	// zero) followed by the first 3 bits of c (also zero).
+	if val, err := m.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0 {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+
+	if p := m.BitPos(); p != 11 {
+		t.Error("Wrong bit position:", p)
+	}
+
+	if _, err := m.Bits(10); err != io.EOF {
+		t.Error("Expected EOF:", err)
+	}
+}
@@ -0,0 +1,97 @@
+package bitstream
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	errUnreadNegative = errors.New("bitstream: cannot unread a negative number of bits")
+	errUnreadRange    = errors.New("bitstream: cannot unread past the current byte")
+)
+
+// PeekBits returns the next bits up to a max of 64 without advancing the
+// reader. A subsequent call to Bits, Bytes, Byte, or Read will see the same
+// bits again.
+func (r *Reader) PeekBits(nBits int) (val uint64, err error) {
+	if nBits > 64 {
+		panic("Can only read 64 bits at a time.")
+	}
+
+	avail := 0
+	if r.offset < 8 {
+		avail = int(8 - r.offset)
+	}
+
+	need := nBits - avail
+	if need < 0 {
+		need = 0
+	}
+	extraBytes := (need + 7) / 8
+
+	var peeked []byte
+	if extraBytes > 0 {
+		peeked, err = r.reader.Peek(extraBytes)
+		if err != nil && len(peeked) == extraBytes {
+			err = nil
+		}
+	}
+
+	offset := r.offset
+	bits := r.bits
+	idx := 0
+
+	var bitOffset uint
+	remaining := nBits
+	for remaining > 0 {
+		if offset == 8 {
+			if idx >= len(peeked) {
+				if err != nil {
+					return val, err
+				}
+				return val, io.EOF
+			}
+			bits = peeked[idx]
+			idx++
+			offset = 0
+		}
+
+		toRead := uint(remaining)
+		if toRead > 8-offset {
+			toRead = 8 - offset
+		}
+
+		if r.shiftUp {
+			val = (val << toRead) | uint64(bits>>offset)&((1<<toRead)-1)
+		} else {
+			var mask byte = ((1 << toRead) - 1) << offset
+			val |= (uint64(mask&bits) >> offset) << bitOffset
+		}
+
+		bitOffset += toRead
+		offset += toRead
+		remaining -= int(toRead)
+	}
+
+	return val, nil
+}
+
+// UnreadBits rewinds the reader by nBits within the current internal byte.
+// It returns an error if nBits is negative, or if it would rewind past the
+// start of the byte currently cached by the reader, including the case
+// where no byte has actually been read into the cache yet (e.g. right
+// after New, Reset, or a byte-aligned Seek).
+func (r *Reader) UnreadBits(nBits int) error {
+	if nBits < 0 {
+		return errUnreadNegative
+	}
+	if uint(nBits) > r.offset {
+		return errUnreadRange
+	}
+	if nBits > 0 && r.offset == 8 && !r.bitsValid {
+		return errUnreadRange
+	}
+
+	r.offset -= uint(nBits)
+	return nil
+}
@@ -0,0 +1,211 @@
+package bitstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// partialReader returns at most n bytes per Read call, to exercise Peek
+// against short reads of the underlying reader.
+type partialReader struct {
+	r io.Reader
+	n int
+}
+
+func (p *partialReader) Read(b []byte) (int, error) {
+	if len(b) > p.n {
+		b = b[:p.n]
+	}
+	return p.r.Read(b)
+}
+
+func TestReader_PeekBits(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+
+	if val, err := b.PeekBits(12); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("0101 0000 1111") {
+		t.Errorf("Wrong Value: % 03X", val)
+	}
+
+	// Peek must not have advanced the reader.
+	if val, err := b.Bits(8); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("0000 1111") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+	if val, err := b.Bits(8); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_PeekBitsHigh(t *testing.T) {
+	data := []byte{toBin("0000 0000"), toBin("0001 1001")}
+
+	b := NewShiftUp(bytes.NewReader(data))
+
+	if val, err := b.PeekBits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != 0x0 {
+		t.Error("Wrong value:", val)
+	}
+
+	if val, err := b.Bits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != 0x0 {
+		t.Error("Wrong value:", val)
+	}
+	if val, err := b.Bits(7); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != 0x9 {
+		t.Error("Wrong value:", val)
+	}
+}
+
+func TestReader_PeekBitsEOF(t *testing.T) {
+	data := []byte{0xFF}
+
+	b := New(bytes.NewReader(data))
+
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, err := b.PeekBits(8); err != io.EOF {
+		t.Error("Expected EOF:", err)
+	}
+
+	// Peek on EOF must not have advanced the reader either.
+	if val, err := b.Bits(4); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0xF {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_PeekBitsShortReads(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101"), toBin("1111 0000")}
+
+	readers := []io.Reader{
+		iotest.OneByteReader(bytes.NewReader(data)),
+		&partialReader{r: bytes.NewReader(data), n: 1},
+	}
+
+	for _, underlying := range readers {
+		b := New(underlying)
+
+		if val, err := b.PeekBits(20); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		} else if val != 0x0A50F {
+			t.Errorf("Wrong Value: % 05X", val)
+		}
+
+		if val, err := b.Bits(20); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		} else if val != 0x0A50F {
+			t.Errorf("Wrong Value: % 05X", val)
+		}
+	}
+}
+
+func TestReader_UnreadBits(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+
+	if _, err := b.Bits(6); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if err := b.UnreadBits(6); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("0000 1111") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_UnreadBitsHigh(t *testing.T) {
+	data := []byte{toBin("0000 0000"), toBin("0001 1001")}
+
+	b := NewShiftUp(bytes.NewReader(data))
+
+	if _, err := b.Bits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if err := b.UnreadBits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if val, err := b.Bits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != 0x0 {
+		t.Error("Wrong value:", val)
+	}
+}
+
+func TestReader_UnreadBitsAcrossByteBoundary(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+
+	// Consume the first byte entirely, then read one bit into the
+	// second: only that one bit is available to unread.
+	if _, err := b.Bits(9); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if err := b.UnreadBits(2); err != errUnreadRange {
+		t.Error("Expected errUnreadRange:", err)
+	}
+
+	if err := b.UnreadBits(1); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+}
+
+func TestReader_UnreadBitsNegative(t *testing.T) {
+	b := New(bytes.NewReader([]byte{0x00}))
+	if err := b.UnreadBits(-1); err != errUnreadNegative {
+		t.Error("Expected errUnreadNegative:", err)
+	}
+}
+
+func TestReader_UnreadBitsNoCachedByte(t *testing.T) {
+	b := New(bytes.NewReader([]byte{0xFF}))
+
+	// A fresh Reader has no byte cached yet, even though offset == 8.
+	if err := b.UnreadBits(3); err != errUnreadRange {
+		t.Error("Expected errUnreadRange:", err)
+	}
+
+	if val, err := b.Bits(3); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("111") {
+		t.Errorf("Wrong value: % 02X", val)
+	}
+}
+
+func TestReader_UnreadBitsAfterReset(t *testing.T) {
+	b := New(bytes.NewReader([]byte{0xFF}))
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	b.Reset(bytes.NewReader([]byte{0x0F}))
+
+	// Reset clears the cached byte, even though offset == 8 again.
+	if err := b.UnreadBits(1); err != errUnreadRange {
+		t.Error("Expected errUnreadRange:", err)
+	}
+}
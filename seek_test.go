@@ -0,0 +1,226 @@
+package bitstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestReader_SeekStart(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101"), toBin("1111 0000")}
+
+	b := New(bytes.NewReader(data))
+
+	if _, err := b.Bits(20); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if pos, err := b.Seek(8, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if pos != 8 {
+		t.Error("Wrong position:", pos)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_SeekCurrent(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if pos, err := b.Seek(4, io.SeekCurrent); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if pos != 8 {
+		t.Error("Wrong position:", pos)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_SeekEnd(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(bytes.NewReader(data))
+
+	if pos, err := b.Seek(-8, io.SeekEnd); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if pos != 8 {
+		t.Error("Wrong position:", pos)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_SeekEndUnknownLen(t *testing.T) {
+	b := New(struct{ io.Reader }{bytes.NewReader([]byte{0x00})})
+
+	if _, err := b.Seek(0, io.SeekEnd); err != errUnknownStreamLen {
+		t.Error("Expected errUnknownStreamLen:", err)
+	}
+}
+
+func TestReader_SeekHigh(t *testing.T) {
+	data := []byte{toBin("0000 0000"), toBin("0001 1001")}
+
+	b := NewShiftUp(bytes.NewReader(data))
+
+	if _, err := b.Bits(5); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, err := b.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if val, err := b.Bits(5); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0x0 {
+		t.Error("Wrong value:", val)
+	}
+	if val, err := b.Bits(7); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0x9 {
+		t.Error("Wrong value:", val)
+	}
+}
+
+func TestReader_SeekWithAlignAndBytes(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101"), toBin("1111 0000")}
+
+	b := New(bytes.NewReader(data))
+
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	b.Align()
+
+	dst := make([]byte, 2)
+	if err := b.Bytes(dst, 16); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, err := b.Seek(8, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	dst = make([]byte, 1)
+	if err := b.Bytes(dst, 8); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if dst[0] != toBin("1010 0101") {
+		t.Errorf("Wrong value: % 02X", dst[0])
+	}
+}
+
+func TestReader_SeekNonSeekableForward(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(struct{ io.Reader }{bytes.NewReader(data)})
+
+	if pos, err := b.Seek(8, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if pos != 8 {
+		t.Error("Wrong position:", pos)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != toBinInt("1010 0101") {
+		t.Errorf("Wrong Value: % 02X", val)
+	}
+}
+
+func TestReader_SeekNonSeekableBackwards(t *testing.T) {
+	data := []byte{toBin("0000 1111"), toBin("1010 0101")}
+
+	b := New(struct{ io.Reader }{bytes.NewReader(data)})
+
+	if _, err := b.Bits(8); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	if _, err := b.Seek(0, io.SeekStart); err != errSeekBackwards {
+		t.Error("Expected errSeekBackwards:", err)
+	}
+}
+
+func TestReader_SeekNegative(t *testing.T) {
+	b := New(bytes.NewReader([]byte{0x00}))
+
+	if _, err := b.Seek(-1, io.SeekStart); err != errNegativeSeek {
+		t.Error("Expected errNegativeSeek:", err)
+	}
+}
+
+func TestReader_SeekInvalidWhence(t *testing.T) {
+	b := New(bytes.NewReader([]byte{0x00}))
+
+	if _, err := b.Seek(0, 99); err != errInvalidWhence {
+		t.Error("Expected errInvalidWhence:", err)
+	}
+}
+
+func TestReader_Reset(t *testing.T) {
+	data1 := []byte{0xFF}
+	data2 := []byte{0x0F}
+
+	b := New(bytes.NewReader(data1))
+	if _, err := b.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	b.Reset(bytes.NewReader(data2))
+
+	if p := b.BitPos(); p != 0 {
+		t.Error("Wrong bit position:", p)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0x0F {
+		t.Errorf("Wrong value: % 02X", val)
+	}
+}
+
+func TestReader_SeekPreAdvancedSeeker(t *testing.T) {
+	data := []byte{0xAA, 0xAA, 0xCC, 0xDD}
+
+	src := bytes.NewReader(data)
+	if _, err := src.Seek(2, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	b := New(src)
+
+	if n := b.BitLen(); n != 16 {
+		t.Error("Wrong bit length:", n)
+	}
+
+	if pos, err := b.Seek(0, io.SeekStart); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if pos != 0 {
+		t.Error("Wrong position:", pos)
+	}
+
+	if val, err := b.Bits(8); err != nil {
+		t.Error("Unexpected Error:", err)
+	} else if val != 0xCC {
+		t.Errorf("Wrong value: % 02X", val)
+	}
+}
@@ -0,0 +1,277 @@
+package bitstream
+
+import (
+	"bufio"
+	"io"
+)
+
+// Writer writes many different types of values outside byte alignments.
+type Writer struct {
+	writer *bufio.Writer
+
+	shiftUp bool
+
+	offset uint
+	bits   byte
+}
+
+// NewWriter constructs a writer that shifts the next byte up to become
+// the most significant bits, the inverse of the reader created by New.
+func NewWriter(writer io.Writer) *Writer {
+	return &Writer{
+		offset: 8,
+		writer: bufio.NewWriter(writer),
+	}
+}
+
+// NewWriterShiftUp constructs a writer that shifts the current byte up to
+// become the most significant bits, the inverse of the reader created by
+// NewShiftUp.
+func NewWriterShiftUp(writer io.Writer) *Writer {
+	return &Writer{
+		offset:  8,
+		writer:  bufio.NewWriter(writer),
+		shiftUp: true,
+	}
+}
+
+// WriteBits writes the low nBits of val.
+func (w *Writer) WriteBits(val uint64, nBits int) error {
+	if w.shiftUp {
+		return w.writeBitsHigh(val, nBits)
+	}
+	return w.writeBitsLow(val, nBits)
+}
+
+// WriteBytes writes the requested number of bits out of a byte array.
+func (w *Writer) WriteBytes(src []byte, nBits int) error {
+	if w.shiftUp {
+		return w.writeBytesHigh(src, nBits)
+	}
+	return w.writeBytesLow(src, nBits)
+}
+
+// Align pads the remainder of the current byte's bits with zeroes and
+// flushes it so that the next write starts byte-aligned.
+func (w *Writer) Align() error {
+	if w.offset == 8 {
+		return nil
+	}
+
+	err := w.writer.WriteByte(w.bits)
+	w.bits = 0
+	w.offset = 8
+	return err
+}
+
+// Flush writes any buffered data to the underlying io.Writer. It does not
+// byte-align a partially filled byte; use Align for that.
+func (w *Writer) Flush() error {
+	return w.writer.Flush()
+}
+
+// Close aligns the writer and flushes the underlying buffer. It does not
+// close the underlying io.Writer.
+func (w *Writer) Close() error {
+	if err := w.Align(); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func (w *Writer) writeBitsLow(val uint64, nBits int) (err error) {
+	if nBits > 64 {
+		panic("Can only write 64 bits at a time.")
+	}
+
+	if w.offset == 8 && nBits == 8 {
+		return w.writer.WriteByte(byte(val))
+	}
+
+	for nBits > 0 {
+		if w.offset == 8 {
+			w.offset = 0
+			w.bits = 0
+		}
+
+		toWrite := uint(nBits)
+		if toWrite > 8-w.offset {
+			toWrite = 8 - w.offset
+		}
+
+		var mask byte = (1 << toWrite) - 1
+
+		w.bits |= (byte(val) & mask) << w.offset
+		val >>= toWrite
+		w.offset += toWrite
+		nBits -= int(toWrite)
+
+		if w.offset == 8 {
+			if err = w.writer.WriteByte(w.bits); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) writeBitsHigh(val uint64, nBits int) (err error) {
+	if nBits > 64 {
+		panic("Can only write 64 bits at a time.")
+	}
+
+	if w.offset == 8 && nBits == 8 {
+		return w.writer.WriteByte(byte(val))
+	}
+
+	bitsLeft := nBits
+	for bitsLeft > 0 {
+		if w.offset == 8 {
+			w.offset = 0
+			w.bits = 0
+		}
+
+		toWrite := uint(bitsLeft)
+		if toWrite > 8-w.offset {
+			toWrite = 8 - w.offset
+		}
+
+		var mask uint64 = (1 << toWrite) - 1
+		chunk := byte((val >> uint(bitsLeft-int(toWrite))) & mask)
+
+		w.bits |= chunk << w.offset
+		w.offset += toWrite
+		bitsLeft -= int(toWrite)
+
+		if w.offset == 8 {
+			if err = w.writer.WriteByte(w.bits); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteByte writes a single byte to the writer.
+func (w *Writer) WriteByte(b byte) error {
+	if w.offset == 8 {
+		return w.writer.WriteByte(b)
+	}
+
+	return w.WriteBits(uint64(b), 8)
+}
+
+// Write whole bytes to the writer.
+func (w *Writer) Write(src []byte) (int, error) {
+	if w.offset == 8 {
+		return w.writer.Write(src)
+	}
+
+	for i, b := range src {
+		if err := w.WriteBits(uint64(b), 8); err != nil {
+			return i, err
+		}
+	}
+
+	return len(src), nil
+}
+
+func (w *Writer) writeBytesLow(src []byte, nBits int) (err error) {
+	if len(src) < (nBits+7)/8 {
+		return bufferTooSmall
+	}
+
+	var byteOffset int
+	var bitOffset uint
+
+	for nBits > 0 {
+		if w.offset == 8 {
+			w.offset = 0
+			w.bits = 0
+		}
+
+		if bitOffset == 8 {
+			bitOffset = 0
+			byteOffset++
+		}
+
+		maskSize := uint(nBits)
+		if maskSize > 8-bitOffset {
+			maskSize = 8 - bitOffset
+		}
+		if maskSize > 8-w.offset {
+			maskSize = 8 - w.offset
+		}
+
+		var mask byte = (1 << maskSize) - 1
+
+		chunk := (src[byteOffset] >> bitOffset) & mask
+		w.bits |= chunk << w.offset
+		bitOffset += maskSize
+		w.offset += maskSize
+		nBits -= int(maskSize)
+
+		if w.offset == 8 {
+			if err = w.writer.WriteByte(w.bits); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) writeBytesHigh(src []byte, nBits int) (err error) {
+	if len(src) < (nBits+7)/8 {
+		return bufferTooSmall
+	}
+
+	var byteOffset int
+	var bitOffset uint
+
+	for nBits > 0 {
+		if w.offset == 8 {
+			w.offset = 0
+			w.bits = 0
+		}
+
+		if bitOffset == 8 {
+			bitOffset = 0
+			byteOffset++
+		}
+
+		maskSize := uint(nBits)
+		if maskSize > 8-bitOffset {
+			maskSize = 8 - bitOffset
+		}
+		if maskSize > 8-w.offset {
+			maskSize = 8 - w.offset
+		}
+
+		var mask byte = (1 << maskSize) - 1
+
+		// A partial last byte holds its bits right-aligned, as the
+		// NewShiftUp reader's Bytes returns them.
+		byteBits := uint(8)
+		if bitOffset+uint(nBits) < 8 {
+			byteBits = bitOffset + uint(nBits)
+		}
+
+		chunk := (src[byteOffset] >> (byteBits - bitOffset - maskSize)) & mask
+		w.bits |= chunk << w.offset
+		bitOffset += maskSize
+		w.offset += maskSize
+		nBits -= int(maskSize)
+
+		if w.offset == 8 {
+			if err = w.writer.WriteByte(w.bits); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package bitstream
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+var writer io.Writer = &Writer{}
+
+func TestWriter_Bits(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+
+	if err := w.WriteBits(toBinInt("01111"), 5); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(toBinInt("101000"), 6); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(toBinInt("0"), 1); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(toBinInt("010"), 3); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(toBinInt("01"), 2); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(toBinInt("111 000"), 6); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.Align(); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+
+	want := []byte{toBin("0000 1111"), toBin("1010 0101"), toBin("0111 0000")}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Wrong bytes: % 02X want % 02X", buf.Bytes(), want)
+	}
+}
+
+func TestWriter_BitsHigh(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriterShiftUp(buf)
+
+	if err := w.WriteBits(0x0, 5); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(0x9, 7); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.WriteBits(0x1, 4); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.Align(); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Error("Unexpected Error:", err)
+	}
+
+	want := []byte{toBin("0000 0000"), toBin("0001 1001")}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("Wrong bytes: % 02X want % 02X", buf.Bytes(), want)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		shift bool
+		vals  []uint64
+		nBits []int
+	}{
+		{"low-simple", false, []uint64{0x1F, 0x3, 0x0, 0x7F}, []int{5, 2, 1, 7}},
+		{"low-64", false, []uint64{0xDEADBEEFCAFEBABE}, []int{64}},
+		{"high-simple", true, []uint64{0x1F, 0x3, 0x0, 0x7F}, []int{5, 2, 1, 7}},
+		{"high-64", true, []uint64{0xDEADBEEFCAFEBABE}, []int{64}},
+		{"mixed-bytes", false, []uint64{0xFF, 0xAB, 0x5, 0x3FF}, []int{8, 8, 3, 10}},
+		{"mixed-bytes-high", true, []uint64{0xFF, 0xAB, 0x5, 0x3FF}, []int{8, 8, 3, 10}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			var w *Writer
+			if test.shift {
+				w = NewWriterShiftUp(buf)
+			} else {
+				w = NewWriter(buf)
+			}
+
+			for i, val := range test.vals {
+				if err := w.WriteBits(val, test.nBits[i]); err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+			}
+			if err := w.Align(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal("Unexpected Error:", err)
+			}
+
+			var r *Reader
+			if test.shift {
+				r = NewShiftUp(bytes.NewReader(buf.Bytes()))
+			} else {
+				r = New(bytes.NewReader(buf.Bytes()))
+			}
+
+			mask := func(nBits int) uint64 {
+				if nBits == 64 {
+					return ^uint64(0)
+				}
+				return (uint64(1) << uint(nBits)) - 1
+			}
+
+			for i, want := range test.vals {
+				got, err := r.Bits(test.nBits[i])
+				if err != nil {
+					t.Fatal("Unexpected Error:", err)
+				}
+				if got != want&mask(test.nBits[i]) {
+					t.Errorf("value %d: got % X want % X", i, got, want&mask(test.nBits[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestWriter_BytesRoundTrip(t *testing.T) {
+	for _, shift := range []bool{false, true} {
+		buf := new(bytes.Buffer)
+		var w *Writer
+		if shift {
+			w = NewWriterShiftUp(buf)
+		} else {
+			w = NewWriter(buf)
+		}
+
+		if _, err := w.Write([]byte{0xDE, 0xAD}); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if err := w.WriteBits(toBinInt("101"), 3); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if err := w.WriteBytes([]byte{0xBE, 0xEF}, 13); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+
+		var r *Reader
+		if shift {
+			r = NewShiftUp(bytes.NewReader(buf.Bytes()))
+		} else {
+			r = New(bytes.NewReader(buf.Bytes()))
+		}
+
+		dst := make([]byte, 2)
+		if n, err := r.Read(dst); err != nil || n != 2 {
+			t.Fatal("Unexpected Read:", n, err)
+		} else if dst[0] != 0xDE || dst[1] != 0xAD {
+			t.Errorf("Wrong bytes: % 02X", dst)
+		}
+
+		if val, err := r.Bits(3); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		} else if val != toBinInt("101") {
+			t.Errorf("Wrong value: % X", val)
+		}
+
+		dst = make([]byte, 2)
+		if err := r.Bytes(dst, 13); err != nil {
+			t.Fatal("Unexpected Error:", err)
+		}
+	}
+}
+
+func TestWriter_Byte(t *testing.T) {
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf)
+
+	if err := w.WriteBits(toBinInt("1111"), 4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if err := w.WriteByte(0xFF); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	}
+
+	r := New(bytes.NewReader(buf.Bytes()))
+	if val, err := r.Bits(4); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if val != toBinInt("1111") {
+		t.Errorf("Wrong value: % X", val)
+	}
+	if b, err := r.Byte(); err != nil {
+		t.Fatal("Unexpected Error:", err)
+	} else if b != 0xFF {
+		t.Errorf("Wrong value: % X", b)
+	}
+}